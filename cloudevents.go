@@ -0,0 +1,223 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// EventFormat selects the wire format used to publish and consume events
+type EventFormat string
+
+const (
+	// FormatNative is the SDK's bespoke Event JSON envelope (default)
+	FormatNative EventFormat = "native"
+
+	// FormatCloudEventsStructured encodes the event as a single CloudEvents 1.0 JSON document
+	FormatCloudEventsStructured EventFormat = "cloudevents_structured"
+
+	// FormatCloudEventsBinary encodes CloudEvents 1.0 attributes as ce_* kafka headers
+	// and writes the raw payload as the message value
+	FormatCloudEventsBinary EventFormat = "cloudevents_binary"
+
+	// FormatSchemaRegistry encodes Payload through BrokerConfig.SchemaRegistry in Confluent wire
+	// format and writes the CloudEvents-style attributes and AdditionalFields as kafka headers; the
+	// other Event fields are not carried, see schemaEvent.go
+	FormatSchemaRegistry EventFormat = "schema_registry"
+)
+
+const (
+	ceSpecVersion     = "1.0"
+	ceHeaderPrefix    = "ce_"
+	ceContentTypeJSON = "application/cloudevents+json"
+	ceDataContentType = "application/json"
+	headerContentType = "content-type"
+)
+
+// cloudEvent is the JSON representation of a CloudEvents 1.0 structured-mode event
+type cloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	ID              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	Type            string                 `json:"type"`
+	Time            string                 `json:"time,omitempty"`
+	DataContentType string                 `json:"datacontenttype,omitempty"`
+	Data            map[string]interface{} `json:"data,omitempty"`
+	TraceID         string                 `json:"traceid,omitempty"`
+	Namespace       string                 `json:"namespace,omitempty"`
+	ClientID        string                 `json:"clientid,omitempty"`
+	UserID          string                 `json:"userid,omitempty"`
+	SessionID       string                 `json:"sessionid,omitempty"`
+}
+
+// ceSource returns the CloudEvents "source" attribute for an event
+func ceSource(event *Event) string {
+	if event.ServiceName != "" {
+		return event.ServiceName
+	}
+
+	return "eventstream-go-sdk"
+}
+
+// constructCloudEventStructured builds a kafka.Message carrying event as a structured-mode CloudEvent
+func constructCloudEventStructured(event *Event) (kafka.Message, error) {
+	ce := cloudEvent{
+		SpecVersion:     ceSpecVersion,
+		ID:              event.ID,
+		Source:          ceSource(event),
+		Type:            event.EventName,
+		Time:            event.Timestamp,
+		DataContentType: ceDataContentType,
+		Data:            event.Payload,
+		TraceID:         event.TraceID,
+		Namespace:       event.Namespace,
+		ClientID:        event.ClientID,
+		UserID:          event.UserID,
+		SessionID:       event.SessionID,
+	}
+
+	value, err := json.Marshal(ce)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	return kafka.Message{
+		Key:   []byte(event.ID),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: headerContentType, Value: []byte(ceContentTypeJSON)},
+		},
+	}, nil
+}
+
+// constructCloudEventBinary builds a kafka.Message carrying event as a binary-mode CloudEvent,
+// with CE attributes written as ce_* headers and the raw payload as the message value
+func constructCloudEventBinary(event *Event) (kafka.Message, error) {
+	value, err := json.Marshal(event.Payload)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	headers := []kafka.Header{
+		{Key: ceHeaderPrefix + "id", Value: []byte(event.ID)},
+		{Key: ceHeaderPrefix + "source", Value: []byte(ceSource(event))},
+		{Key: ceHeaderPrefix + "type", Value: []byte(event.EventName)},
+		{Key: ceHeaderPrefix + "specversion", Value: []byte(ceSpecVersion)},
+		{Key: headerContentType, Value: []byte(ceDataContentType)},
+	}
+
+	if event.Timestamp != "" {
+		headers = append(headers, kafka.Header{Key: ceHeaderPrefix + "time", Value: []byte(event.Timestamp)})
+	}
+
+	for key, val := range map[string]string{
+		"traceid":   event.TraceID,
+		"namespace": event.Namespace,
+		"clientid":  event.ClientID,
+		"userid":    event.UserID,
+		"sessionid": event.SessionID,
+	} {
+		if val != "" {
+			headers = append(headers, kafka.Header{Key: ceHeaderPrefix + key, Value: []byte(val)})
+		}
+	}
+
+	return kafka.Message{
+		Key:     []byte(event.ID),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+// detectEventFormat inspects a consumed message's headers to figure out which wire format produced it
+func detectEventFormat(message kafka.Message) EventFormat {
+	if headerValue(message, schemaHeaderID) != "" {
+		return FormatSchemaRegistry
+	}
+
+	for _, header := range message.Headers {
+		if header.Key == ceHeaderPrefix+"specversion" {
+			return FormatCloudEventsBinary
+		}
+	}
+
+	if headerValue(message, headerContentType) == ceContentTypeJSON {
+		return FormatCloudEventsStructured
+	}
+
+	return FormatNative
+}
+
+// headerValue returns the value of the first header matching key, case-sensitive, or "" if absent
+func headerValue(message kafka.Message, key string) string {
+	for _, header := range message.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// unmarshalCloudEventStructured hydrates an Event from a structured-mode CloudEvents message
+func unmarshalCloudEventStructured(message kafka.Message) (*Event, error) {
+	var ce cloudEvent
+
+	err := json.Unmarshal(message.Value, &ce)
+	if err != nil {
+		return &Event{}, err
+	}
+
+	return &Event{
+		ID:          ce.ID,
+		EventName:   ce.Type,
+		Namespace:   ce.Namespace,
+		ClientID:    ce.ClientID,
+		UserID:      ce.UserID,
+		TraceID:     ce.TraceID,
+		SessionID:   ce.SessionID,
+		Timestamp:   ce.Time,
+		ServiceName: ce.Source,
+		Payload:     ce.Data,
+	}, nil
+}
+
+// unmarshalCloudEventBinary hydrates an Event from a binary-mode CloudEvents message
+func unmarshalCloudEventBinary(message kafka.Message) (*Event, error) {
+	var payload map[string]interface{}
+
+	if len(message.Value) > 0 {
+		if err := json.Unmarshal(message.Value, &payload); err != nil {
+			return &Event{}, err
+		}
+	}
+
+	return &Event{
+		ID:          headerValue(message, ceHeaderPrefix+"id"),
+		EventName:   headerValue(message, ceHeaderPrefix+"type"),
+		Namespace:   headerValue(message, ceHeaderPrefix+"namespace"),
+		ClientID:    headerValue(message, ceHeaderPrefix+"clientid"),
+		UserID:      headerValue(message, ceHeaderPrefix+"userid"),
+		TraceID:     headerValue(message, ceHeaderPrefix+"traceid"),
+		SessionID:   headerValue(message, ceHeaderPrefix+"sessionid"),
+		Timestamp:   headerValue(message, ceHeaderPrefix+"time"),
+		ServiceName: headerValue(message, ceHeaderPrefix+"source"),
+		Payload:     payload,
+	}, nil
+}
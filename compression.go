@@ -0,0 +1,54 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/lz4"
+	"github.com/segmentio/kafka-go/snappy"
+	"github.com/segmentio/kafka-go/zstd"
+)
+
+// Compression selects the codec used to compress published messages
+type Compression string
+
+const (
+	// CompressionNone disables compression (default)
+	CompressionNone Compression = ""
+
+	CompressionSnappy Compression = "snappy"
+	CompressionLz4    Compression = "lz4"
+	CompressionZstd   Compression = "zstd"
+)
+
+// codec builds the kafka.CompressionCodec for c, or nil for CompressionNone
+func (c Compression) codec() (kafka.CompressionCodec, error) {
+	switch c {
+	case CompressionNone:
+		return nil, nil
+	case CompressionSnappy:
+		return snappy.NewCompressionCodec(), nil
+	case CompressionLz4:
+		return lz4.NewCompressionCodec(), nil
+	case CompressionZstd:
+		return zstd.NewCompressionCodec(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", c)
+	}
+}
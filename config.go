@@ -0,0 +1,77 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BrokerConfig is an optional configuration for the client
+type BrokerConfig struct {
+	StrictValidation bool
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+
+	// Format selects the wire format used to publish and consume events.
+	// Defaults to FormatNative when left unset.
+	Format EventFormat
+
+	// Security configures SASL authentication and TLS for connecting to secured brokers
+	Security *Security
+
+	// Logger receives KafkaClient's structured logs. Defaults to a no-op logger, so the SDK no
+	// longer mutates the global logrus logger. Use NewLogrusLogger/NewZapLogger/NewZerologLogger/
+	// NewSlogLogger to plug in an existing logging stack.
+	Logger Logger
+
+	// TracerProvider and MeterProvider configure the OpenTelemetry tracer/meter KafkaClient emits
+	// publish/consume spans and metrics through. Both default to the global otel providers.
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+
+	// Writer tuning, applied to the long-lived kafka.Writer KafkaClient keeps per topic
+	BatchSize    int
+	BatchTimeout time.Duration
+	RequiredAcks int
+	Async        bool
+	Compression  Compression
+
+	// Idempotent requires acks from all in-sync replicas (equivalent to setting RequiredAcks to
+	// kafka.RequireAll) so a retried write can't be acknowledged by a replica that never durably
+	// received a prior attempt. segmentio/kafka-go has no producer-id/sequence-number support, so
+	// this does not dedupe retried writes and does not provide exactly-once semantics; a retry can
+	// still append a duplicate message after a transient write error.
+	Idempotent bool
+
+	// SchemaRegistry, once set, lets Format FormatSchemaRegistry encode/decode Payload through it in
+	// Confluent wire format; AdditionalFields is carried as a JSON message header and is not
+	// schema-encoded. The schema type, subject and schema document are selected per publish via
+	// PublishBuilder.Schema.
+	SchemaRegistry *SchemaRegistry
+
+	// AutoRegisterSchema registers the PublishBuilder.Schema document on every publish instead of
+	// looking up the subject's latest version. The registry is idempotent for an unchanged schema.
+	AutoRegisterSchema bool
+
+	// SchemaCompatibility, when set, is applied to a subject before AutoRegisterSchema registers
+	// a new version under it
+	SchemaCompatibility CompatibilityMode
+}
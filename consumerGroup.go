@@ -0,0 +1,353 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	defaultSessionTimeout    = 30 * time.Second
+	defaultHeartbeatInterval = 3 * time.Second
+	defaultRebalanceTimeout  = 30 * time.Second
+)
+
+// StartOffset selects where a ConsumerGroup begins reading a topic it has no committed offset for
+type StartOffset string
+
+const (
+	// StartOffsetFirst resumes from the earliest available offset
+	StartOffsetFirst StartOffset = "first"
+
+	// StartOffsetLast resumes from the latest offset (default)
+	StartOffsetLast StartOffset = "last"
+
+	// StartOffsetTimestamp resumes from ConsumerGroupConfig.StartOffsetTime. Not currently
+	// supported: kafka-go's Reader.SetOffset(At) rejects seeking once a GroupID is set (see
+	// reader.go), and a ConsumerGroup always sets one, so NewConsumerGroup rejects this option
+	// rather than accept it and silently fall back to StartOffsetLast.
+	StartOffsetTimestamp StartOffset = "timestamp"
+)
+
+// PartitionAssignment selects the strategy used to assign topic partitions to group members
+type PartitionAssignment string
+
+const (
+	// PartitionAssignmentRange assigns each member a contiguous range of partitions per topic (default)
+	PartitionAssignmentRange PartitionAssignment = "range"
+
+	// PartitionAssignmentRoundRobin spreads partitions evenly across members
+	PartitionAssignmentRoundRobin PartitionAssignment = "roundrobin"
+
+	// PartitionAssignmentSticky minimizes partition movement across rebalances. kafka-go does not
+	// ship a cooperative-sticky balancer, so this currently falls back to round-robin.
+	PartitionAssignmentSticky PartitionAssignment = "sticky"
+)
+
+// groupBalancer builds the kafka.GroupBalancer for a PartitionAssignment strategy
+func groupBalancer(assignment PartitionAssignment) kafka.GroupBalancer {
+	switch assignment {
+	case PartitionAssignmentRoundRobin, PartitionAssignmentSticky:
+		return kafka.RoundRobinGroupBalancer{}
+	default:
+		return kafka.RangeGroupBalancer{}
+	}
+}
+
+// ConsumerGroupConfig is an optional configuration for a ConsumerGroup
+type ConsumerGroupConfig struct {
+	// SessionTimeout is the timeout used by the broker to detect a failed member, defaults to 30s
+	SessionTimeout time.Duration
+
+	// HeartbeatInterval is the expected time between heartbeats to the group coordinator, defaults to 3s
+	HeartbeatInterval time.Duration
+
+	// RebalanceTimeout bounds how long the coordinator waits for members to rejoin during a
+	// rebalance, defaults to 30s
+	RebalanceTimeout time.Duration
+
+	// StartOffset selects where to resume a topic with no committed offset, defaults to StartOffsetLast
+	StartOffset StartOffset
+
+	// StartOffsetTime is the timestamp paired with StartOffsetTimestamp. NewConsumerGroup rejects
+	// StartOffsetTimestamp, so this field is not read.
+	StartOffsetTime time.Time
+
+	// PartitionAssignment selects the partition assignment strategy, defaults to PartitionAssignmentRange
+	PartitionAssignment PartitionAssignment
+
+	// Security configures SASL authentication and TLS for connecting to secured brokers
+	Security *Security
+
+	// Logger receives ConsumerGroup's structured logs, defaults to a no-op logger
+	Logger Logger
+
+	// SchemaRegistry decodes messages produced with FormatSchemaRegistry before they reach Handle
+	SchemaRegistry *SchemaRegistry
+
+	// SchemaTarget returns a fresh pointer to decode a FormatSchemaRegistry message's payload into
+	// directly, attached to the handled Event.SchemaValue. Required to consume a Protobuf subject,
+	// since its decoded value can't be represented as the map[string]interface{} Event.Payload
+	// otherwise decodes into.
+	SchemaTarget func() interface{}
+}
+
+// PartitionLag reports the consumer lag of a single partition, as observed from the last message
+// fetched from it
+type PartitionLag struct {
+	Partition int
+	Lag       int64
+}
+
+// ConsumerGroup owns a single kafka-go reader per (group, topic-set) and fans out the messages it
+// fetches to handlers registered by event name via Handle. Unlike KafkaClient.Register, offsets
+// are committed explicitly through reader.CommitMessages once the matching handler returns
+// successfully, so a message's own offset is committed only after it has been processed; a
+// message whose handler failed is still passed by a later commit on its partition, see Handle.
+type ConsumerGroup struct {
+	groupID string
+	topics  []string
+
+	reader         *kafka.Reader
+	logger         Logger
+	schemaRegistry *SchemaRegistry
+	schemaTarget   func() interface{}
+
+	handlers     map[string]func(ctx context.Context, event *Event) error
+	handlersLock sync.RWMutex
+
+	lag     map[int]int64
+	lagLock sync.Mutex
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewConsumerGroup creates a ConsumerGroup consuming topics as a member of groupID. The returned
+// group does not start fetching until Start is called.
+func NewConsumerGroup(brokers []string, groupID string, topics []string, config ...*ConsumerGroupConfig) (*ConsumerGroup, error) {
+	readerConfig := kafka.ReaderConfig{
+		Brokers:           brokers,
+		GroupID:           groupID,
+		GroupTopics:       topics,
+		MaxBytes:          defaultReaderSize,
+		SessionTimeout:    defaultSessionTimeout,
+		HeartbeatInterval: defaultHeartbeatInterval,
+		RebalanceTimeout:  defaultRebalanceTimeout,
+		StartOffset:       kafka.LastOffset,
+		GroupBalancers:    []kafka.GroupBalancer{groupBalancer(PartitionAssignmentRange)},
+	}
+
+	logger := Logger(noopLogger{})
+
+	var schemaRegistry *SchemaRegistry
+
+	var schemaTarget func() interface{}
+
+	if len(config) > 0 && config[0] != nil {
+		cfg := config[0]
+
+		if cfg.SessionTimeout != 0 {
+			readerConfig.SessionTimeout = cfg.SessionTimeout
+		}
+
+		if cfg.HeartbeatInterval != 0 {
+			readerConfig.HeartbeatInterval = cfg.HeartbeatInterval
+		}
+
+		if cfg.RebalanceTimeout != 0 {
+			readerConfig.RebalanceTimeout = cfg.RebalanceTimeout
+		}
+
+		if cfg.StartOffset == StartOffsetFirst {
+			readerConfig.StartOffset = kafka.FirstOffset
+		}
+
+		if cfg.PartitionAssignment != "" {
+			readerConfig.GroupBalancers = []kafka.GroupBalancer{groupBalancer(cfg.PartitionAssignment)}
+		}
+
+		if cfg.Security != nil {
+			dialer := &kafka.Dialer{}
+
+			if err := applySecurity(dialer, cfg.Security); err != nil {
+				return nil, fmt.Errorf("unable to apply security config: %v", err)
+			}
+
+			readerConfig.Dialer = dialer
+		}
+
+		if cfg.Logger != nil {
+			logger = cfg.Logger
+		}
+
+		if cfg.StartOffset == StartOffsetTimestamp {
+			return nil, errors.New("StartOffsetTimestamp is not supported for a ConsumerGroup: " +
+				"kafka-go rejects seeking a reader that has a GroupID set, which a ConsumerGroup " +
+				"always does; use StartOffsetFirst/StartOffsetLast, or reset the group's committed " +
+				"offsets out of band before starting it")
+		}
+
+		schemaRegistry = cfg.SchemaRegistry
+		schemaTarget = cfg.SchemaTarget
+	}
+
+	reader := kafka.NewReader(readerConfig)
+
+	return &ConsumerGroup{
+		groupID:        groupID,
+		topics:         topics,
+		reader:         reader,
+		logger:         logger,
+		schemaRegistry: schemaRegistry,
+		schemaTarget:   schemaTarget,
+		handlers:       make(map[string]func(ctx context.Context, event *Event) error),
+		lag:            make(map[int]int64),
+	}, nil
+}
+
+// Handle registers the handler invoked for messages whose event name matches eventName, or every
+// message when eventName is empty. Returning an error from handler leaves the message's offset
+// uncommitted and the group moves on to the next message. Committing a later message of the same
+// partition commits past it, so it is redelivered only if the group restarts or rebalances before
+// a later offset of that partition is committed.
+func (group *ConsumerGroup) Handle(eventName string, handler func(ctx context.Context, event *Event) error) {
+	group.handlersLock.Lock()
+	defer group.handlersLock.Unlock()
+
+	group.handlers[eventName] = handler
+}
+
+// Start begins fetching and dispatching messages in a background goroutine until ctx is cancelled,
+// Stop is called, or a fetch fails (the error is logged and the goroutine exits). Each message's handler runs to completion, or is cancelled alongside ctx,
+// before its offset is committed, so a rebalance can't strand an in-flight message as lost or
+// silently skip it.
+func (group *ConsumerGroup) Start(ctx context.Context) error {
+	if group.done != nil {
+		return errors.New("consumer group already started")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	group.cancel = cancel
+	group.done = make(chan struct{})
+
+	logger := group.logger.With("group_id", group.groupID, "topics", group.topics)
+	logger.Debug("starting consumer group")
+
+	go func() {
+		defer close(group.done)
+
+		for {
+			message, err := group.reader.FetchMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				logger.Error("unable to fetch message", "error", err)
+
+				return
+			}
+
+			group.recordLag(message)
+			group.dispatch(ctx, message)
+		}
+	}()
+
+	return nil
+}
+
+// dispatch unmarshals message, runs the handler registered for its event name (if any), and
+// commits its offset once the handler returns successfully
+func (group *ConsumerGroup) dispatch(ctx context.Context, message kafka.Message) {
+	logger := group.logger.With("topic", message.Topic, "partition", message.Partition, "offset", message.Offset)
+
+	event, err := unmarshal(ctx, message, group.schemaRegistry, group.schemaTarget)
+	if err != nil {
+		logger.Error("unable to unmarshal message", "error", err)
+		return
+	}
+
+	handler := group.handlerFor(event.EventName)
+	if handler == nil {
+		return
+	}
+
+	if err := handler(ctx, event); err != nil {
+		logger.Error("handler returned an error, offset left uncommitted", "error", err)
+		return
+	}
+
+	if err := group.reader.CommitMessages(ctx, message); err != nil {
+		logger.Error("unable to commit offset", "error", err)
+	}
+}
+
+// handlerFor returns the handler registered for eventName, falling back to the catch-all handler
+// registered with an empty event name, or nil if neither is registered
+func (group *ConsumerGroup) handlerFor(eventName string) func(ctx context.Context, event *Event) error {
+	group.handlersLock.RLock()
+	defer group.handlersLock.RUnlock()
+
+	if handler, ok := group.handlers[eventName]; ok {
+		return handler
+	}
+
+	return group.handlers[""]
+}
+
+// recordLag tracks the consumer lag of message's partition, derived from its high water mark
+func (group *ConsumerGroup) recordLag(message kafka.Message) {
+	group.lagLock.Lock()
+	defer group.lagLock.Unlock()
+
+	group.lag[message.Partition] = message.HighWaterMark - message.Offset - 1
+}
+
+// Stats returns the current consumer lag for every partition fetched from so far
+func (group *ConsumerGroup) Stats() []PartitionLag {
+	group.lagLock.Lock()
+	defer group.lagLock.Unlock()
+
+	stats := make([]PartitionLag, 0, len(group.lag))
+
+	for partition, lag := range group.lag {
+		stats = append(stats, PartitionLag{Partition: partition, Lag: lag})
+	}
+
+	return stats
+}
+
+// Stop cancels any in-flight fetch/dispatch, waits for the consuming goroutine to exit, and
+// closes the underlying reader
+func (group *ConsumerGroup) Stop() error {
+	if group.cancel != nil {
+		group.cancel()
+	}
+
+	if group.done != nil {
+		<-group.done
+	}
+
+	return group.reader.Close()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+// Event is a representation of event that is published or consumed from the event stream
+type Event struct {
+	ID        string `json:"id,omitempty"`
+	EventName string `json:"eventName,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	ClientID  string `json:"clientId,omitempty"`
+	UserID    string `json:"userId,omitempty"`
+
+	// TraceID and SpanContext are written into the message when the event is constructed, before
+	// KafkaClient.Publish starts that topic's span, so the message does not carry the started span
+	// in them; that span is propagated via the message's traceparent header instead (see
+	// telemetry.startPublishSpan). startPublishSpan then sets both to the started span's trace and
+	// span ids on the Event handed to PublishBuilder.ErrorCallback. On a consumed Event they carry
+	// whatever value the producer's message held.
+	TraceID     string `json:"traceId,omitempty"`
+	SpanContext string `json:"spanContext,omitempty"`
+
+	SessionID        string                 `json:"sessionId,omitempty"`
+	Timestamp        string                 `json:"timestamp,omitempty"`
+	Version          int                    `json:"version,omitempty"`
+	EventID          int                    `json:"eventId,omitempty"`
+	EventType        int                    `json:"eventType,omitempty"`
+	EventLevel       int                    `json:"eventLevel,omitempty"`
+	ServiceName      string                 `json:"serviceName,omitempty"`
+	ClientIDs        []string               `json:"clientIds,omitempty"`
+	TargetUserIDs    []string               `json:"targetUserIds,omitempty"`
+	TargetNamespace  string                 `json:"targetNamespace,omitempty"`
+	Privacy          bool                   `json:"privacy,omitempty"`
+	AdditionalFields map[string]interface{} `json:"additionalFields,omitempty"`
+	Payload          map[string]interface{} `json:"payload,omitempty"`
+
+	// SchemaValue holds the typed value a FormatSchemaRegistry message's payload was decoded into
+	// when the subscriber registered a SchemaTarget factory, e.g. a concrete proto.Message for a
+	// Protobuf subject. It is never part of the wire payload and is nil for every other format, or
+	// for FormatSchemaRegistry without a registered SchemaTarget (Payload is used instead).
+	SchemaValue interface{} `json:"-"`
+}
@@ -21,13 +21,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
 	"github.com/segmentio/kafka-go"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -61,9 +62,33 @@ type KafkaClient struct {
 
 	// mutex to avoid runtime races to access subscribers map
 	lock sync.RWMutex
+
+	// wire format used to publish and consume events, defaults to FormatNative
+	format EventFormat
+
+	// structured logger, defaults to a no-op logger
+	logger Logger
+
+	// tracer/meter and instruments used to emit publish/consume spans and metrics
+	telemetry *telemetry
+
+	// long-lived writers, reused across publishes and keyed by the fully constructed topic name
+	writers map[string]*kafka.Writer
+
+	// mutex to avoid runtime races to access writers map
+	writerLock sync.Mutex
+
+	// schema registry client backing Format FormatSchemaRegistry, nil unless BrokerConfig.SchemaRegistry is set
+	schemaRegistry *SchemaRegistry
+
+	// whether to auto-register the PublishBuilder.Schema document on every publish
+	autoRegisterSchema bool
+
+	// compatibility mode applied to a subject before auto-registering a new schema version under it
+	schemaCompatibility CompatibilityMode
 }
 
-func setConfig(writerConfig *kafka.WriterConfig, readerConfig *kafka.ReaderConfig, config *BrokerConfig) {
+func setConfig(writerConfig *kafka.WriterConfig, readerConfig *kafka.ReaderConfig, config *BrokerConfig) error {
 	if config.ReadTimeout != 0 {
 		writerConfig.ReadTimeout = config.WriteTimeout
 	}
@@ -72,39 +97,61 @@ func setConfig(writerConfig *kafka.WriterConfig, readerConfig *kafka.ReaderConfi
 		writerConfig.WriteTimeout = config.WriteTimeout
 	}
 
-	if config.DialTimeout != 0 {
+	if config.DialTimeout != 0 || config.Security != nil {
 		dialer := &kafka.Dialer{
 			Timeout: config.DialTimeout,
 		}
+
+		if config.Security != nil {
+			if err := applySecurity(dialer, config.Security); err != nil {
+				return err
+			}
+		}
+
 		writerConfig.Dialer = dialer
 		readerConfig.Dialer = dialer
 	}
 
-	setLogLevel(config.LogMode)
-}
+	if config.BatchSize != 0 {
+		writerConfig.BatchSize = config.BatchSize
+	}
 
-func setLogLevel(logMode string) {
-	switch logMode {
-	case DebugLevel:
-		log.SetLevel(log.DebugLevel)
-	case InfoLevel:
-		log.SetLevel(log.InfoLevel)
-	case WarnLevel:
-		log.SetLevel(log.WarnLevel)
-	case ErrorLevel:
-		log.SetLevel(log.ErrorLevel)
-	default:
-		log.SetOutput(ioutil.Discard)
+	if config.BatchTimeout != 0 {
+		writerConfig.BatchTimeout = config.BatchTimeout
+	}
+
+	if config.RequiredAcks != 0 {
+		writerConfig.RequiredAcks = config.RequiredAcks
 	}
+
+	writerConfig.Async = config.Async
+
+	if config.Compression != CompressionNone {
+		codec, err := config.Compression.codec()
+		if err != nil {
+			return err
+		}
+
+		writerConfig.CompressionCodec = codec
+	}
+
+	if config.Idempotent {
+		// see BrokerConfig.Idempotent: this only strengthens RequiredAcks, it does not dedupe
+		writerConfig.RequiredAcks = int(kafka.RequireAll)
+	}
+
+	return nil
 }
 
 // newKafkaClient create a new instance of KafkaClient
 func newKafkaClient(brokers []string, prefix string, config ...*BrokerConfig) *KafkaClient {
-	log.Info("create new kafka client")
-
 	writerConfig := &kafka.WriterConfig{
-		Brokers:  brokers,
-		Balancer: &kafka.LeastBytes{},
+		Brokers: brokers,
+		// kafka.Hash routes by the message key when one is set (what PublishBuilder.Key's
+		// per-user/per-session ordering guarantee needs) and falls back to round-robin when it
+		// isn't, so a single writer per topic can serve both keyed and unkeyed publishes correctly
+		// regardless of which kind reaches the topic first.
+		Balancer: &kafka.Hash{},
 	}
 
 	readerConfig := &kafka.ReaderConfig{
@@ -116,9 +163,50 @@ func newKafkaClient(brokers []string, prefix string, config ...*BrokerConfig) *K
 	// only uses first KafkaConfig arguments
 	var strictValidation bool
 
+	format := FormatNative
+	logger := Logger(noopLogger{})
+
+	var tracerProvider trace.TracerProvider
+
+	var meterProvider metric.MeterProvider
+
+	var schemaRegistry *SchemaRegistry
+
+	var autoRegisterSchema bool
+
+	var schemaCompatibility CompatibilityMode
+
 	if len(config) > 0 {
-		setConfig(writerConfig, readerConfig, config[0])
+		if config[0].Logger != nil {
+			logger = config[0].Logger
+		}
+
+		if err := setConfig(writerConfig, readerConfig, config[0]); err != nil {
+			logger.Error("invalid broker config, falling back to defaults", "error", err)
+		}
+
 		strictValidation = config[0].StrictValidation
+
+		if config[0].Format != "" {
+			format = config[0].Format
+		}
+
+		tracerProvider = config[0].TracerProvider
+		meterProvider = config[0].MeterProvider
+
+		schemaRegistry = config[0].SchemaRegistry
+		autoRegisterSchema = config[0].AutoRegisterSchema
+		schemaCompatibility = config[0].SchemaCompatibility
+	}
+
+	logger.Debug("create new kafka client")
+
+	clientTelemetry, err := newTelemetry(tracerProvider, meterProvider)
+	if err != nil {
+		logger.Error("unable to set up telemetry with the configured providers, falling back to global providers",
+			"error", err)
+
+		clientTelemetry, _ = newTelemetry(nil, nil)
 	}
 
 	return &KafkaClient{
@@ -127,25 +215,39 @@ func newKafkaClient(brokers []string, prefix string, config ...*BrokerConfig) *K
 		publishConfig:    *writerConfig,
 		subscribeConfig:  *readerConfig,
 		subscribers:      make(map[*SubscribeBuilder]struct{}),
+		format:           format,
+		logger:           logger,
+		telemetry:        clientTelemetry,
+		writers:          make(map[string]*kafka.Writer),
+
+		schemaRegistry:      schemaRegistry,
+		autoRegisterSchema:  autoRegisterSchema,
+		schemaCompatibility: schemaCompatibility,
 	}
 }
 
 // Publish send event to single or multiple topic with exponential backoff retry
 func (client *KafkaClient) Publish(publishBuilder *PublishBuilder) error {
 	if publishBuilder == nil {
-		log.Error(errPubNilEvent)
+		client.logger.Error(errPubNilEvent.Error())
 		return errPubNilEvent
 	}
 
+	logger := client.logger.With("event_name", publishBuilder.eventName)
+
 	err := validatePublishEvent(publishBuilder, client.strictValidation)
 	if err != nil {
-		log.Error(err)
+		logger.Error(err.Error())
 		return err
 	}
 
-	message, event, err := ConstructEvent(publishBuilder)
+	message, event, err := ConstructEvent(publishBuilder, client.format, &schemaRegistryOptions{
+		registry:      client.schemaRegistry,
+		autoRegister:  client.autoRegisterSchema,
+		compatibility: client.schemaCompatibility,
+	})
 	if err != nil {
-		log.Errorf("unable to construct event: %s , error: %v", publishBuilder.eventName, err)
+		logger.Error("unable to construct event", "error", err)
 		return fmt.Errorf("unable to construct event : %s , error : %v", publishBuilder.eventName, err)
 	}
 
@@ -153,58 +255,106 @@ func (client *KafkaClient) Publish(publishBuilder *PublishBuilder) error {
 
 	for _, pubTopic := range publishBuilder.topic {
 		topic := pubTopic
+		topicLogger := logger.With("topic", topic)
+
+		topicMessage := message
+		topicMessage.Headers = append([]kafka.Header{}, message.Headers...)
+
+		// Each topic's goroutine gets its own Event so startPublishSpan can stamp its own
+		// TraceID/SpanContext without racing the other topics' goroutines over the same pointer.
+		topicEvent := &Event{}
+		*topicEvent = *event
 
 		go func() {
-			err = backoff.RetryNotify(func() error {
-				return client.publishEvent(publishBuilder.ctx, topic, publishBuilder.eventName, config, message)
+			start := time.Now()
+			attrs := topicAttributes(topic, publishBuilder.eventName)
+
+			ctx, span := client.telemetry.startPublishSpan(publishBuilder.ctx, topic, &topicMessage, topicEvent)
+			defer span.End()
+
+			err := backoff.RetryNotify(func() error {
+				return client.publishEvent(ctx, topic, publishBuilder.eventName, config, topicMessage)
 			}, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxBackOffCount),
 				func(err error, _ time.Duration) {
-					log.Debugf("retrying publish event: error %v: ", err)
+					topicLogger.Debug("retrying publish event", "error", err)
 				})
+
+			client.telemetry.publishDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
 			if err != nil {
-				log.Errorf("unable to publish event. topic: %s , event: %s , error: %v", topic,
-					publishBuilder.eventName, err)
+				topicLogger.Error("unable to publish event", "error", err)
+				client.telemetry.publishErrors.Add(ctx, 1, metric.WithAttributes(attrs...))
+				span.RecordError(err)
 
 				if publishBuilder.errorCallback != nil {
-					publishBuilder.errorCallback(event, err)
+					publishBuilder.errorCallback(topicEvent, err)
 				}
 
 				return
 			}
 
-			log.Debugf("successfully publish event %s into topic %s", publishBuilder.eventName,
-				topic)
+			topicLogger.Debug("successfully published event")
 		}()
 	}
 
 	return nil
 }
 
-// Publish send event to a topic
+// publishEvent send event to a topic, reusing the long-lived writer for topicName across calls
 func (client *KafkaClient) publishEvent(ctx context.Context, topic, eventName string, config kafka.WriterConfig,
 	message kafka.Message) error {
 	topicName := constructTopic(client.prefix, topic)
-	log.Debugf("publish event %s into topic %s", eventName,
-		topicName)
-
-	config.Topic = topicName
-	writer := kafka.NewWriter(config)
+	logger := client.logger.With("topic", topicName, "event_name", eventName)
+	logger.Debug("publish event")
 
-	defer func() {
-		_ = writer.Close()
-	}()
+	writer := client.getWriter(topicName, config)
 
 	err := writer.WriteMessages(ctx, message)
 	if err != nil {
-		log.Errorf("unable to publish event to kafka. topic: %s , error: %v", topicName, err)
+		logger.Error("unable to publish event to kafka", "error", err)
 		return fmt.Errorf("unable to publish event to kafka. topic: %s , error: %v", topicName, err)
 	}
 
 	return nil
 }
 
-// ConstructEvent construct event message
-func ConstructEvent(publishBuilder *PublishBuilder) (kafka.Message, *Event, error) {
+// getWriter returns the long-lived writer for topicName, lazily creating and caching it on first use
+func (client *KafkaClient) getWriter(topicName string, config kafka.WriterConfig) *kafka.Writer {
+	client.writerLock.Lock()
+	defer client.writerLock.Unlock()
+
+	if writer, ok := client.writers[topicName]; ok {
+		return writer
+	}
+
+	config.Topic = topicName
+	writer := kafka.NewWriter(config)
+	client.writers[topicName] = writer
+
+	return writer
+}
+
+// Close drains and shuts down every long-lived writer created by Publish
+func (client *KafkaClient) Close() error {
+	client.writerLock.Lock()
+	defer client.writerLock.Unlock()
+
+	var firstErr error
+
+	for topicName, writer := range client.writers {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("unable to close writer for topic %s: %v", topicName, err)
+		}
+	}
+
+	client.writers = make(map[string]*kafka.Writer)
+
+	return firstErr
+}
+
+// ConstructEvent construct event message, encoding it according to format. schema is only
+// consulted when format is FormatSchemaRegistry; pass nil otherwise.
+func ConstructEvent(publishBuilder *PublishBuilder, format EventFormat, schema *schemaRegistryOptions) (kafka.Message, *Event, error) {
 	id := generateID()
 	event := &Event{
 		ID:               id,
@@ -229,16 +379,34 @@ func ConstructEvent(publishBuilder *PublishBuilder) (kafka.Message, *Event, erro
 		Payload:          publishBuilder.payload,
 	}
 
-	eventBytes, err := marshal(event)
+	var (
+		message kafka.Message
+		err     error
+	)
+
+	switch format {
+	case FormatCloudEventsStructured:
+		message, err = constructCloudEventStructured(event)
+	case FormatCloudEventsBinary:
+		message, err = constructCloudEventBinary(event)
+	case FormatSchemaRegistry:
+		message, err = constructSchemaRegistryEvent(publishBuilder.ctx, event, schema,
+			publishBuilder.schemaType, publishBuilder.schemaSubject, publishBuilder.schemaText)
+	default:
+		var eventBytes []byte
+		eventBytes, err = marshal(event)
+		message = kafka.Message{Key: []byte(id), Value: eventBytes}
+	}
+
 	if err != nil {
-		log.Errorf("unable to marshal event: %s , error: %v", publishBuilder.eventName, err)
 		return kafka.Message{}, event, err
 	}
 
-	return kafka.Message{
-		Key:   []byte(id),
-		Value: eventBytes,
-	}, event, nil
+	if publishBuilder.key != nil {
+		message.Key = publishBuilder.key(event)
+	}
+
+	return message, event, nil
 }
 
 // unregister unregister subscriber
@@ -249,16 +417,17 @@ func (client *KafkaClient) unregister(subscribeBuilder *SubscribeBuilder) {
 // Register register callback function and then subscribe topic
 func (client *KafkaClient) Register(subscribeBuilder *SubscribeBuilder) error {
 	if subscribeBuilder == nil {
-		log.Error(errSubNilEvent)
+		client.logger.Error(errSubNilEvent.Error())
 		return errSubNilEvent
 	}
 
-	log.Debugf("register callback to consume topic %s , event: %s", subscribeBuilder.topic,
-		subscribeBuilder.eventName)
+	logger := client.logger.With("topic", subscribeBuilder.topic, "event_name", subscribeBuilder.eventName,
+		"group_id", subscribeBuilder.groupID)
+	logger.Debug("register callback to consume topic")
 
 	err := validateSubscribeEvent(subscribeBuilder)
 	if err != nil {
-		log.Error(err)
+		logger.Error(err.Error())
 		return err
 	}
 
@@ -267,7 +436,7 @@ func (client *KafkaClient) Register(subscribeBuilder *SubscribeBuilder) error {
 
 	isRegistered, err := client.registerSubscriber(subscribeBuilder)
 	if err != nil {
-		log.Errorf("unable to register callback. error: %v", err)
+		logger.Error("unable to register callback", "error", err)
 		return err
 	}
 
@@ -294,12 +463,14 @@ func (client *KafkaClient) Register(subscribeBuilder *SubscribeBuilder) error {
 		for {
 			select {
 			case <-subscribeBuilder.ctx.Done():
-				subscribeBuilder.callback(subscribeBuilder.ctx, nil, subscribeBuilder.ctx.Err())
+				if subscribeBuilder.callback != nil {
+					subscribeBuilder.callback(subscribeBuilder.ctx, nil, subscribeBuilder.ctx.Err())
+				}
 				return
 			default:
 				consumerMessage, errRead := reader.ReadMessage(subscribeBuilder.ctx)
 				if errRead != nil {
-					log.Error("unable to subscribe topic from kafka. error: ", errRead)
+					logger.Error("unable to subscribe topic from kafka", "error", errRead)
 					return
 				}
 
@@ -311,6 +482,17 @@ func (client *KafkaClient) Register(subscribeBuilder *SubscribeBuilder) error {
 	return nil
 }
 
+// RegisterWithRetry registers an error-returning callback and subscribes to the topic, applying
+// bounded exponential-backoff retry and routing exhausted/panicking messages to the dead-letter
+// topic configured via SubscribeBuilder.Retry
+func (client *KafkaClient) RegisterWithRetry(subscribeBuilder *SubscribeBuilder) error {
+	if subscribeBuilder != nil && subscribeBuilder.callbackWithRetry == nil {
+		return errors.New("callback with retry can't be nil")
+	}
+
+	return client.Register(subscribeBuilder)
+}
+
 // registerSubscriber add callback to map with topic and eventName as a key
 func (client *KafkaClient) registerSubscriber(subscribeBuilder *SubscribeBuilder) (
 	isRegistered bool, err error) {
@@ -333,11 +515,13 @@ func (client *KafkaClient) registerSubscriber(subscribeBuilder *SubscribeBuilder
 
 // processMessage process a message from kafka
 func (client *KafkaClient) processMessage(subscribeBuilder *SubscribeBuilder, message kafka.Message) {
-	log.Debugf("process message from topic: %s, groupID : %s", message.Topic, subscribeBuilder.groupID)
+	logger := client.logger.With("topic", message.Topic, "group_id", subscribeBuilder.groupID,
+		"partition", message.Partition, "offset", message.Offset)
+	logger.Debug("process message")
 
-	event, err := unmarshal(message)
+	event, err := unmarshal(subscribeBuilder.ctx, message, client.schemaRegistry, subscribeBuilder.schemaTarget)
 	if err != nil {
-		log.Error("unable to unmarshal message from subscribe in kafka. error: ", err)
+		logger.Error("unable to unmarshal message from subscribe in kafka", "error", err)
 		return
 	}
 
@@ -346,31 +530,63 @@ func (client *KafkaClient) processMessage(subscribeBuilder *SubscribeBuilder, me
 		return
 	}
 
-	client.runCallback(subscribeBuilder, event, message)
-}
+	attrs := topicAttributes(message.Topic, event.EventName)
 
-// unmarshal unmarshal received message into event struct
-func unmarshal(message kafka.Message) (*Event, error) {
-	var event Event
+	if !message.Time.IsZero() {
+		client.telemetry.consumeLag.Record(subscribeBuilder.ctx, time.Since(message.Time).Seconds(),
+			metric.WithAttributes(attrs...))
+	}
 
-	err := json.Unmarshal(message.Value, &event)
-	if err != nil {
-		return &Event{}, err
+	ctx, span := client.telemetry.startConsumeSpan(subscribeBuilder.ctx, message)
+	defer span.End()
+
+	start := time.Now()
+
+	if subscribeBuilder.callbackWithRetry != nil {
+		client.runCallbackWithRetry(ctx, subscribeBuilder, event, message)
+	} else {
+		client.runCallback(ctx, subscribeBuilder, event, message)
 	}
 
-	return &event, nil
+	client.telemetry.consumeDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+}
+
+// unmarshal unmarshal received message into event struct, detecting the wire format from
+// the message headers/content-type so native, structured/binary CloudEvents and schema registry
+// producers can all be consumed through the same callback. registry and schemaTarget are only
+// consulted for messages produced with FormatSchemaRegistry; pass nil for both if it's unused.
+func unmarshal(ctx context.Context, message kafka.Message, registry *SchemaRegistry,
+	schemaTarget func() interface{}) (*Event, error) {
+	switch detectEventFormat(message) {
+	case FormatCloudEventsBinary:
+		return unmarshalCloudEventBinary(message)
+	case FormatCloudEventsStructured:
+		return unmarshalCloudEventStructured(message)
+	case FormatSchemaRegistry:
+		return unmarshalSchemaRegistryEvent(ctx, message, registry, schemaTarget)
+	default:
+		var event Event
+
+		err := json.Unmarshal(message.Value, &event)
+		if err != nil {
+			return &Event{}, err
+		}
+
+		return &event, nil
+	}
 }
 
 // runCallback run callback function when receive an event
 func (client *KafkaClient) runCallback(
+	ctx context.Context,
 	subscribeBuilder *SubscribeBuilder,
 	event *Event,
 	consumerMessage kafka.Message,
 ) {
-	log.Debugf("run callback for topic: %s , event name: %s, groupID: %s", consumerMessage.Topic,
-		event.EventName, subscribeBuilder.groupID)
+	client.logger.With("topic", consumerMessage.Topic, "event_name", event.EventName,
+		"group_id", subscribeBuilder.groupID).Debug("run callback")
 
-	subscribeBuilder.callback(subscribeBuilder.ctx, &Event{
+	subscribeBuilder.callback(ctx, &Event{
 		ID:               event.ID,
 		ClientID:         event.ClientID,
 		EventName:        event.EventName,
@@ -391,5 +607,101 @@ func (client *KafkaClient) runCallback(
 		Version:          event.Version,
 		AdditionalFields: event.AdditionalFields,
 		Payload:          event.Payload,
+		SchemaValue:      event.SchemaValue,
 	}, nil)
 }
+
+// runCallbackWithRetry runs an error-returning callback with bounded exponential backoff,
+// recovering panics, and forwards the original message to the dead-letter topic (if configured)
+// once retries are exhausted
+func (client *KafkaClient) runCallbackWithRetry(
+	ctx context.Context,
+	subscribeBuilder *SubscribeBuilder,
+	event *Event,
+	consumerMessage kafka.Message,
+) {
+	logger := client.logger.With("topic", consumerMessage.Topic, "event_name", event.EventName,
+		"group_id", subscribeBuilder.groupID)
+	logger.Debug("run callback with retry")
+
+	attrs := topicAttributes(consumerMessage.Topic, event.EventName)
+
+	retryConfig := subscribeBuilder.retryConfig
+	if retryConfig == nil {
+		retryConfig = &RetryConfig{}
+	}
+
+	maxRetries := retryConfig.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	expBackoff := backoff.NewExponentialBackOff()
+
+	expBackoff.InitialInterval = retryConfig.InitialBackoff
+	if expBackoff.InitialInterval <= 0 {
+		expBackoff.InitialInterval = defaultInitialBackoff
+	}
+
+	expBackoff.MaxInterval = retryConfig.MaxBackoff
+	if expBackoff.MaxInterval <= 0 {
+		expBackoff.MaxInterval = defaultMaxBackoff
+	}
+
+	firstSeenAt := time.Now().UTC().Format(time.RFC3339)
+	retryCount := 0
+
+	err := backoff.RetryNotify(func() error {
+		return client.runCallbackSafely(ctx, subscribeBuilder, event)
+	}, backoff.WithMaxRetries(expBackoff, uint64(maxRetries)),
+		func(err error, _ time.Duration) {
+			retryCount++
+			client.telemetry.consumeRetries.Add(ctx, 1, metric.WithAttributes(attrs...))
+			logger.Debug("retrying callback", "retry", retryCount, "error", err)
+		})
+	if err == nil {
+		return
+	}
+
+	logger.Error("callback exhausted retries", "error", err)
+
+	if retryConfig.DeadLetterTopic == "" {
+		return
+	}
+
+	client.publishToDeadLetter(ctx, retryConfig.DeadLetterTopic, consumerMessage, err,
+		retryCount, firstSeenAt)
+}
+
+// runCallbackSafely invokes callbackWithRetry, recovering any panic into an error so a single
+// poison message can't crash the consumer goroutine
+func (client *KafkaClient) runCallbackSafely(ctx context.Context, subscribeBuilder *SubscribeBuilder, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("callback panicked: %v", r)
+		}
+	}()
+
+	return subscribeBuilder.callbackWithRetry(ctx, event)
+}
+
+// publishToDeadLetter forwards an undeliverable message to dlqTopic via the existing publish
+// path, annotated with headers describing why and when it was first seen
+func (client *KafkaClient) publishToDeadLetter(ctx context.Context, dlqTopic string, message kafka.Message,
+	callbackErr error, retryCount int, firstSeenAt string) {
+	dlqMessage := kafka.Message{
+		Key:   message.Key,
+		Value: message.Value,
+		Headers: append(message.Headers,
+			kafka.Header{Key: "x-original-topic", Value: []byte(message.Topic)},
+			kafka.Header{Key: "x-error", Value: []byte(callbackErr.Error())},
+			kafka.Header{Key: "x-retry-count", Value: []byte(strconv.Itoa(retryCount))},
+			kafka.Header{Key: "x-first-seen-at", Value: []byte(firstSeenAt)},
+		),
+	}
+
+	err := client.publishEvent(ctx, dlqTopic, "dead-letter", client.publishConfig, dlqMessage)
+	if err != nil {
+		client.logger.Error("unable to publish message to dead letter topic", "topic", dlqTopic, "error", err)
+	}
+}
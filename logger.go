@@ -0,0 +1,41 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+// Logger is the structured logging interface KafkaClient logs through. keyvals are alternating
+// key/value pairs (e.g. "topic", topic, "event_name", eventName), mirroring the most common
+// structured logging libraries so adapters stay thin. Implement this directly, or use one of the
+// NewXxxLogger adapters, to plug in your own logging stack instead of the default no-op logger.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+
+	// With returns a Logger that prepends keyvals to every subsequent log call
+	With(keyvals ...interface{}) Logger
+}
+
+// noopLogger is the default Logger, used when BrokerConfig.Logger is left unset so the SDK no
+// longer mutates the global logrus logger as a side effect of newKafkaClient
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{})  {}
+func (noopLogger) Info(string, ...interface{})   {}
+func (noopLogger) Warn(string, ...interface{})   {}
+func (noopLogger) Error(string, ...interface{})  {}
+func (n noopLogger) With(...interface{}) Logger  { return n }
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "github.com/sirupsen/logrus"
+
+// logrusLogger adapts a *logrus.Entry to the Logger interface
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+// NewLogrusLogger wraps entry as a Logger, e.g. NewLogrusLogger(logrus.NewEntry(logrus.StandardLogger()))
+func NewLogrusLogger(entry *logrus.Entry) Logger {
+	return logrusLogger{entry: entry}
+}
+
+func (l logrusLogger) Debug(msg string, keyvals ...interface{}) {
+	l.fields(keyvals).Debug(msg)
+}
+
+func (l logrusLogger) Info(msg string, keyvals ...interface{}) {
+	l.fields(keyvals).Info(msg)
+}
+
+func (l logrusLogger) Warn(msg string, keyvals ...interface{}) {
+	l.fields(keyvals).Warn(msg)
+}
+
+func (l logrusLogger) Error(msg string, keyvals ...interface{}) {
+	l.fields(keyvals).Error(msg)
+}
+
+func (l logrusLogger) With(keyvals ...interface{}) Logger {
+	return logrusLogger{entry: l.fields(keyvals)}
+}
+
+// fields converts alternating key/value pairs into a *logrus.Entry carrying them as fields
+func (l logrusLogger) fields(keyvals []interface{}) *logrus.Entry {
+	entry := l.entry
+	if entry == nil {
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	return entry.WithFields(keyvalsToFields(keyvals))
+}
+
+// keyvalsToFields converts alternating key/value pairs into a logrus.Fields map, ignoring a
+// trailing unmatched key
+func keyvalsToFields(keyvals []interface{}) logrus.Fields {
+	fields := make(logrus.Fields, len(keyvals)/2)
+
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			continue
+		}
+
+		fields[key] = keyvals[i+1]
+	}
+
+	return fields
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "go.uber.org/zap"
+
+// zapLogger adapts a *zap.SugaredLogger to the Logger interface
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// NewZapLogger wraps logger as a Logger, e.g. NewZapLogger(zapLogger)
+func NewZapLogger(logger *zap.Logger) Logger {
+	return zapLogger{sugar: logger.Sugar()}
+}
+
+func (l zapLogger) Debug(msg string, keyvals ...interface{}) { l.sugar.Debugw(msg, keyvals...) }
+func (l zapLogger) Info(msg string, keyvals ...interface{})  { l.sugar.Infow(msg, keyvals...) }
+func (l zapLogger) Warn(msg string, keyvals ...interface{})  { l.sugar.Warnw(msg, keyvals...) }
+func (l zapLogger) Error(msg string, keyvals ...interface{}) { l.sugar.Errorw(msg, keyvals...) }
+
+func (l zapLogger) With(keyvals ...interface{}) Logger {
+	return zapLogger{sugar: l.sugar.With(keyvals...)}
+}
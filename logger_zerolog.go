@@ -0,0 +1,74 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "github.com/rs/zerolog"
+
+// zerologLogger adapts a zerolog.Logger to the Logger interface
+type zerologLogger struct {
+	logger zerolog.Logger
+}
+
+// NewZerologLogger wraps logger as a Logger
+func NewZerologLogger(logger zerolog.Logger) Logger {
+	return zerologLogger{logger: logger}
+}
+
+func (l zerologLogger) Debug(msg string, keyvals ...interface{}) {
+	l.event(l.logger.Debug(), keyvals).Msg(msg)
+}
+
+func (l zerologLogger) Info(msg string, keyvals ...interface{}) {
+	l.event(l.logger.Info(), keyvals).Msg(msg)
+}
+
+func (l zerologLogger) Warn(msg string, keyvals ...interface{}) {
+	l.event(l.logger.Warn(), keyvals).Msg(msg)
+}
+
+func (l zerologLogger) Error(msg string, keyvals ...interface{}) {
+	l.event(l.logger.Error(), keyvals).Msg(msg)
+}
+
+func (l zerologLogger) With(keyvals ...interface{}) Logger {
+	ctx := l.logger.With()
+
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			continue
+		}
+
+		ctx = ctx.Interface(key, keyvals[i+1])
+	}
+
+	return zerologLogger{logger: ctx.Logger()}
+}
+
+// event attaches alternating key/value pairs onto a zerolog.Event
+func (l zerologLogger) event(event *zerolog.Event, keyvals []interface{}) *zerolog.Event {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			continue
+		}
+
+		event = event.Interface(key, keyvals[i+1])
+	}
+
+	return event
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rexydwybrundy/eventstream-go-sdk"
+
+// telemetry bundles the tracer, propagator and metric instruments KafkaClient emits spans and
+// metrics through. It is always populated, falling back to the global otel providers when
+// BrokerConfig.TracerProvider/MeterProvider are left unset.
+type telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	publishDuration metric.Float64Histogram
+	publishErrors   metric.Int64Counter
+	consumeLag      metric.Float64Histogram
+	consumeDuration metric.Float64Histogram
+	consumeRetries  metric.Int64Counter
+}
+
+// newTelemetry builds a telemetry from tracerProvider/meterProvider, falling back to the global
+// providers for whichever is nil
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*telemetry, error) {
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+
+	publishDuration, err := meter.Float64Histogram("eventstream.publish.duration")
+	if err != nil {
+		return nil, err
+	}
+
+	publishErrors, err := meter.Int64Counter("eventstream.publish.errors")
+	if err != nil {
+		return nil, err
+	}
+
+	consumeLag, err := meter.Float64Histogram("eventstream.consume.lag")
+	if err != nil {
+		return nil, err
+	}
+
+	consumeDuration, err := meter.Float64Histogram("eventstream.consume.duration")
+	if err != nil {
+		return nil, err
+	}
+
+	consumeRetries, err := meter.Int64Counter("eventstream.consume.retries")
+	if err != nil {
+		return nil, err
+	}
+
+	return &telemetry{
+		tracer:          tracerProvider.Tracer(instrumentationName),
+		propagator:      otel.GetTextMapPropagator(),
+		publishDuration: publishDuration,
+		publishErrors:   publishErrors,
+		consumeLag:      consumeLag,
+		consumeDuration: consumeDuration,
+		consumeRetries:  consumeRetries,
+	}, nil
+}
+
+// kafkaHeaderCarrier adapts a *[]kafka.Header to propagation.TextMapCarrier so the W3C
+// traceparent/tracestate propagator can read and write headers in place
+type kafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	for i, header := range *c.headers {
+		if header.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, header := range *c.headers {
+		keys[i] = header.Key
+	}
+
+	return keys
+}
+
+// startPublishSpan starts a "kafka.publish <topic>" span, injects its context into message's
+// headers via the configured propagator, and records the resulting trace/span ids onto event.
+// event has already been marshaled into message's body by this point, so only the propagated
+// header carries the started span to a consumer; any TraceID/SpanContext copy already embedded in
+// message (e.g. a native or CloudEvents payload) keeps the value it had when the message was
+// constructed, see Event.TraceID.
+func (t *telemetry) startPublishSpan(
+	ctx context.Context, topic string, message *kafka.Message, event *Event,
+) (context.Context, trace.Span) {
+	ctx, span := t.tracer.Start(ctx, fmt.Sprintf("kafka.publish %s", topic), trace.WithSpanKind(trace.SpanKindProducer))
+
+	t.propagator.Inject(ctx, kafkaHeaderCarrier{headers: &message.Headers})
+
+	spanContext := span.SpanContext()
+	event.TraceID = spanContext.TraceID().String()
+	event.SpanContext = spanContext.SpanID().String()
+
+	return ctx, span
+}
+
+// startConsumeSpan extracts the propagated trace context from message's headers and starts the
+// "kafka.consume <topic>" child span the subscriber callback runs under
+func (t *telemetry) startConsumeSpan(ctx context.Context, message kafka.Message) (context.Context, trace.Span) {
+	headers := message.Headers
+	ctx = t.propagator.Extract(ctx, kafkaHeaderCarrier{headers: &headers})
+
+	return t.tracer.Start(ctx, fmt.Sprintf("kafka.consume %s", message.Topic), trace.WithSpanKind(trace.SpanKindConsumer))
+}
+
+// topicAttributes returns the common topic/event_name attribute pair metrics are tagged by
+func topicAttributes(topic, eventName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("topic", topic),
+		attribute.String("event_name", eventName),
+	}
+}
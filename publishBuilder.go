@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "context"
+
+// PublishBuilder defines the required and optional fields to publish an event
+type PublishBuilder struct {
+	topic            []string
+	eventName        string
+	namespace        string
+	clientID         string
+	userID           string
+	traceID          string
+	spanContext      string
+	sessionID        string
+	version          int
+	eventID          int
+	eventType        int
+	eventLevel       int
+	serviceName      string
+	clientIDs        []string
+	targetUserIDs    []string
+	targetNamespace  string
+	privacy          bool
+	additionalFields map[string]interface{}
+	payload          map[string]interface{}
+	errorCallback    func(event *Event, err error)
+	key              func(event *Event) []byte
+	schemaType       SchemaType
+	schemaSubject    string
+	schemaText       string
+	ctx              context.Context
+}
+
+// NewPublish creates a new PublishBuilder
+func NewPublish() *PublishBuilder {
+	return &PublishBuilder{
+		ctx: context.Background(),
+	}
+}
+
+// Topic sets the topic(s) the event will be published to
+func (p *PublishBuilder) Topic(topic ...string) *PublishBuilder {
+	p.topic = append(p.topic, topic...)
+	return p
+}
+
+// EventName sets the event name
+func (p *PublishBuilder) EventName(eventName string) *PublishBuilder {
+	p.eventName = eventName
+	return p
+}
+
+// Payload sets the event payload
+func (p *PublishBuilder) Payload(payload map[string]interface{}) *PublishBuilder {
+	p.payload = payload
+	return p
+}
+
+// Context sets the context used to publish the event
+func (p *PublishBuilder) Context(ctx context.Context) *PublishBuilder {
+	p.ctx = ctx
+	return p
+}
+
+// ErrorCallback sets the function to be called when publish fails
+func (p *PublishBuilder) ErrorCallback(errorCallback func(event *Event, err error)) *PublishBuilder {
+	p.errorCallback = errorCallback
+	return p
+}
+
+// Key sets the function used to derive the kafka partition key for the event, instead of the
+// default random event id. Use this to get ordering guarantees for a given user or session by
+// keying on e.g. UserID or SessionID.
+func (p *PublishBuilder) Key(key func(event *Event) []byte) *PublishBuilder {
+	p.key = key
+	return p
+}
+
+// Schema selects the SchemaRegistry subject and schema used to encode Payload in Confluent wire
+// format. AdditionalFields is not schema-encoded; it is carried as a JSON message header.
+// Requires BrokerConfig.SchemaRegistry and EventFormat FormatSchemaRegistry to take effect.
+func (p *PublishBuilder) Schema(schemaType SchemaType, subject, schemaText string) *PublishBuilder {
+	p.schemaType = schemaType
+	p.schemaSubject = subject
+	p.schemaText = schemaText
+	return p
+}
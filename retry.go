@@ -0,0 +1,41 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "time"
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// RetryConfig configures bounded exponential-backoff retry and dead-letter routing for
+// subscribers registered through KafkaClient.RegisterWithRetry
+type RetryConfig struct {
+	// MaxRetries is the number of attempts after the initial one, defaults to 3
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry, defaults to 100ms
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff delay, defaults to 10s
+	MaxBackoff time.Duration
+
+	// DeadLetterTopic, when set, receives the original message after retries are exhausted
+	DeadLetterTopic string
+}
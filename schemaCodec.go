@@ -0,0 +1,210 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	avro "github.com/hamba/avro/v2"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	confluentMagicByte = 0x00
+	confluentHeaderLen = 5 // magic byte + 4 byte big-endian schema id
+)
+
+// SchemaType selects the encoding used to serialize a publish's Payload through a SchemaRegistry
+type SchemaType string
+
+const (
+	// SchemaTypeAvro encodes using the Avro schema registered for the subject
+	SchemaTypeAvro SchemaType = "AVRO"
+
+	// SchemaTypeProtobuf encodes using a proto.Message's own wire format
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+
+	// SchemaTypeJSONSchema encodes as plain JSON, validated against the registered JSON Schema
+	SchemaTypeJSONSchema SchemaType = "JSON"
+)
+
+// schemaCodec encodes/decodes a Go value against a raw schema document of one SchemaType.
+// PublishBuilder.Schema selects the entry from schemaCodecRegistry matching its SchemaType.
+type schemaCodec interface {
+	Encode(schemaText string, value interface{}) ([]byte, error)
+	Decode(schemaText string, data []byte, out interface{}) error
+}
+
+// schemaCodecRegistry holds the built-in codecs keyed by SchemaType. RegisterSchemaCodec lets
+// callers plug in a different implementation (e.g. goavro instead of hamba/avro) without forking,
+// mirroring RegisterSASLMechanism.
+var schemaCodecRegistry = map[SchemaType]schemaCodec{
+	SchemaTypeAvro:       avroCodec{},
+	SchemaTypeProtobuf:   protobufCodec{},
+	SchemaTypeJSONSchema: jsonSchemaCodec{},
+}
+
+// RegisterSchemaCodec registers or overrides the schemaCodec used for schemaType
+func RegisterSchemaCodec(schemaType SchemaType, codec schemaCodec) {
+	schemaCodecRegistry[schemaType] = codec
+}
+
+// avroCodec encodes/decodes using the hamba/avro library
+type avroCodec struct{}
+
+func (avroCodec) Encode(schemaText string, value interface{}) ([]byte, error) {
+	schema, err := avro.Parse(schemaText)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse avro schema: %v", err)
+	}
+
+	return avro.Marshal(schema, value)
+}
+
+func (avroCodec) Decode(schemaText string, data []byte, out interface{}) error {
+	schema, err := avro.Parse(schemaText)
+	if err != nil {
+		return fmt.Errorf("unable to parse avro schema: %v", err)
+	}
+
+	return avro.Unmarshal(schema, data, out)
+}
+
+// protobufCodec encodes/decodes proto.Message values. The registered schema text isn't needed to
+// serialize (protobuf messages carry their own wire format); the registry entry exists so the
+// subject can still be versioned and compatibility-checked like the other schema types.
+type protobufCodec struct{}
+
+func (protobufCodec) Encode(_ string, value interface{}) ([]byte, error) {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf encoding requires a proto.Message, got %T", value)
+	}
+
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) Decode(_ string, data []byte, out interface{}) error {
+	message, ok := out.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf decoding requires a proto.Message, got %T", out)
+	}
+
+	return proto.Unmarshal(data, message)
+}
+
+// jsonSchemaCodec encodes values as plain JSON, validating against the registered JSON Schema
+type jsonSchemaCodec struct{}
+
+func (jsonSchemaCodec) Encode(schemaText string, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateJSONSchema(schemaText, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (jsonSchemaCodec) Decode(schemaText string, data []byte, out interface{}) error {
+	if err := validateJSONSchema(schemaText, data); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// validateJSONSchema compiles schemaText and validates data against it
+func validateJSONSchema(schemaText string, data []byte) error {
+	compiler := jsonschema.NewCompiler()
+
+	if err := compiler.AddResource("schema.json", strings.NewReader(schemaText)); err != nil {
+		return fmt.Errorf("unable to load json schema: %v", err)
+	}
+
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		return fmt.Errorf("unable to compile json schema: %v", err)
+	}
+
+	var doc interface{}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		return fmt.Errorf("json schema validation failed: %v", err)
+	}
+
+	return nil
+}
+
+// EncodeSchema serializes value against schema in Confluent wire format: a 0x00 magic byte, the
+// 4-byte big-endian schema id, then the schema-encoded payload
+func EncodeSchema(schema *Schema, value interface{}) ([]byte, error) {
+	codec, ok := schemaCodecRegistry[schema.SchemaType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type: %s", schema.SchemaType)
+	}
+
+	encoded, err := codec.Encode(schema.Schema, value)
+	if err != nil {
+		return nil, err
+	}
+
+	wire := make([]byte, confluentHeaderLen, confluentHeaderLen+len(encoded))
+	wire[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(wire[1:confluentHeaderLen], uint32(schema.ID))
+
+	return append(wire, encoded...), nil
+}
+
+// DecodeSchema splits data's Confluent wire header from its schema-encoded payload, fetches the
+// writer schema by id from registry, and decodes the payload into out
+func DecodeSchema(ctx context.Context, registry *SchemaRegistry, data []byte, out interface{}) (*Schema, error) {
+	if len(data) < confluentHeaderLen || data[0] != confluentMagicByte {
+		return nil, errors.New("data is not in Confluent schema registry wire format")
+	}
+
+	id := int(binary.BigEndian.Uint32(data[1:confluentHeaderLen]))
+
+	schema, err := registry.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := schemaCodecRegistry[schema.SchemaType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type: %s", schema.SchemaType)
+	}
+
+	if err := codec.Decode(schema.Schema, data[confluentHeaderLen:], out); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	schemaHeaderID      = "x-schema-id"
+	schemaHeaderSubject = "x-schema-subject"
+	schemaHeaderType    = "x-schema-type"
+	schemaContentType   = "application/x-confluent-wire-format"
+)
+
+// schemaRegistryOptions bundles the KafkaClient-level schema registry wiring ConstructEvent needs
+// to encode a FormatSchemaRegistry publish
+type schemaRegistryOptions struct {
+	registry      *SchemaRegistry
+	autoRegister  bool
+	compatibility CompatibilityMode
+}
+
+// constructSchemaRegistryEvent builds a kafka.Message whose value is event.Payload encoded
+// through opts.registry in Confluent wire format, with id, type, time, source, traceid, namespace,
+// clientid, userid and sessionid carried as ce_* headers (mirroring constructCloudEventBinary's
+// header layout) and AdditionalFields as a JSON header. SpanContext, Version, EventID, EventType,
+// EventLevel, ClientIDs, TargetUserIDs, TargetNamespace and Privacy are not carried.
+func constructSchemaRegistryEvent(ctx context.Context, event *Event, opts *schemaRegistryOptions,
+	schemaType SchemaType, subject, schemaText string) (kafka.Message, error) {
+	if opts == nil || opts.registry == nil {
+		return kafka.Message{}, errors.New("FormatSchemaRegistry requires BrokerConfig.SchemaRegistry")
+	}
+
+	if subject == "" {
+		return kafka.Message{}, errors.New("FormatSchemaRegistry requires PublishBuilder.Schema")
+	}
+
+	var (
+		schema *Schema
+		err    error
+	)
+
+	if opts.autoRegister {
+		schema, err = opts.registry.Register(ctx, subject, schemaType, schemaText, opts.compatibility)
+	} else {
+		schema, err = opts.registry.GetLatest(ctx, subject)
+	}
+
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("unable to resolve schema for subject %s: %v", subject, err)
+	}
+
+	payload := event.Payload
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+
+	value, err := EncodeSchema(schema, payload)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("unable to encode payload: %v", err)
+	}
+
+	additionalFields, err := json.Marshal(event.AdditionalFields)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	headers := []kafka.Header{
+		{Key: headerContentType, Value: []byte(schemaContentType)},
+		{Key: schemaHeaderID, Value: []byte(fmt.Sprint(schema.ID))},
+		{Key: schemaHeaderSubject, Value: []byte(schema.Subject)},
+		{Key: schemaHeaderType, Value: []byte(schema.SchemaType)},
+		{Key: ceHeaderPrefix + "id", Value: []byte(event.ID)},
+		{Key: ceHeaderPrefix + "type", Value: []byte(event.EventName)},
+		{Key: "additional-fields", Value: additionalFields},
+	}
+
+	if event.Timestamp != "" {
+		headers = append(headers, kafka.Header{Key: ceHeaderPrefix + "time", Value: []byte(event.Timestamp)})
+	}
+
+	for key, val := range map[string]string{
+		"traceid":   event.TraceID,
+		"namespace": event.Namespace,
+		"clientid":  event.ClientID,
+		"userid":    event.UserID,
+		"sessionid": event.SessionID,
+		"source":    event.ServiceName,
+	} {
+		if val != "" {
+			headers = append(headers, kafka.Header{Key: ceHeaderPrefix + key, Value: []byte(val)})
+		}
+	}
+
+	return kafka.Message{
+		Key:     []byte(event.ID),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+// unmarshalSchemaRegistryEvent hydrates an Event from a FormatSchemaRegistry message, decoding
+// Payload through registry and rehydrating AdditionalFields from its header. When target is set,
+// the payload is decoded directly into the value it returns and attached to Event.SchemaValue
+// instead; this is required to consume a Protobuf subject, since its decoded value can't be
+// represented as the map[string]interface{} Payload otherwise decodes into.
+func unmarshalSchemaRegistryEvent(ctx context.Context, message kafka.Message, registry *SchemaRegistry,
+	target func() interface{}) (*Event, error) {
+	if registry == nil {
+		return &Event{}, errors.New("FormatSchemaRegistry requires BrokerConfig.SchemaRegistry")
+	}
+
+	event := &Event{
+		ID:          headerValue(message, ceHeaderPrefix+"id"),
+		EventName:   headerValue(message, ceHeaderPrefix+"type"),
+		Namespace:   headerValue(message, ceHeaderPrefix+"namespace"),
+		ClientID:    headerValue(message, ceHeaderPrefix+"clientid"),
+		UserID:      headerValue(message, ceHeaderPrefix+"userid"),
+		TraceID:     headerValue(message, ceHeaderPrefix+"traceid"),
+		SessionID:   headerValue(message, ceHeaderPrefix+"sessionid"),
+		Timestamp:   headerValue(message, ceHeaderPrefix+"time"),
+		ServiceName: headerValue(message, ceHeaderPrefix+"source"),
+	}
+
+	if target != nil {
+		value := target()
+
+		if _, err := DecodeSchema(ctx, registry, message.Value, value); err != nil {
+			return &Event{}, fmt.Errorf("unable to decode payload: %v", err)
+		}
+
+		event.SchemaValue = value
+	} else {
+		var payload map[string]interface{}
+
+		if _, err := DecodeSchema(ctx, registry, message.Value, &payload); err != nil {
+			return &Event{}, fmt.Errorf("unable to decode payload: %v", err)
+		}
+
+		event.Payload = payload
+	}
+
+	if raw := headerValue(message, "additional-fields"); raw != "" && raw != "null" {
+		var additionalFields map[string]interface{}
+
+		if err := json.Unmarshal([]byte(raw), &additionalFields); err != nil {
+			return &Event{}, fmt.Errorf("unable to decode additional fields: %v", err)
+		}
+
+		event.AdditionalFields = additionalFields
+	}
+
+	return event, nil
+}
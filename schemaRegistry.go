@@ -0,0 +1,314 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSchemaCacheTTL = 10 * time.Minute
+
+// CompatibilityMode selects the compatibility check a schema registry enforces for new versions
+// registered under a subject
+type CompatibilityMode string
+
+const (
+	// CompatibilityBackward requires new schemas to be readable by the previous version's consumers
+	CompatibilityBackward CompatibilityMode = "BACKWARD"
+
+	// CompatibilityForward requires the previous version to be readable by new schemas' consumers
+	CompatibilityForward CompatibilityMode = "FORWARD"
+
+	// CompatibilityFull requires both backward and forward compatibility
+	CompatibilityFull CompatibilityMode = "FULL"
+)
+
+// Schema is a single registered schema version, as returned by SchemaRegistry
+type Schema struct {
+	ID         int
+	Subject    string
+	Version    int
+	SchemaType SchemaType
+	Schema     string
+}
+
+// confluentSchema mirrors the JSON shape of the Confluent Schema Registry HTTP API
+type confluentSchema struct {
+	ID         int    `json:"id,omitempty"`
+	Subject    string `json:"subject,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema"`
+}
+
+func (s confluentSchema) toSchema() *Schema {
+	schemaType := SchemaType(s.SchemaType)
+	if schemaType == "" {
+		// the registry omits schemaType for AVRO, its default
+		schemaType = SchemaTypeAvro
+	}
+
+	return &Schema{
+		ID:         s.ID,
+		Subject:    s.Subject,
+		Version:    s.Version,
+		SchemaType: schemaType,
+		Schema:     s.Schema,
+	}
+}
+
+// SchemaRegistryConfig is an optional configuration for a SchemaRegistry client
+type SchemaRegistryConfig struct {
+	// Username and Password configure HTTP basic auth against the registry, if required
+	Username string
+	Password string
+
+	// CacheTTL bounds how long a resolved schema is cached by id/subject, defaults to 10 minutes
+	CacheTTL time.Duration
+
+	// HTTPClient is used to call the registry, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// cachedSchema pairs a Schema with the time it was cached, to enforce SchemaRegistryConfig.CacheTTL
+type cachedSchema struct {
+	schema   *Schema
+	cachedAt time.Time
+}
+
+// SchemaRegistry is a client for a Confluent-compatible schema registry (`/subjects/{subject}/versions`,
+// `/schemas/ids/{id}`), used by PublishBuilder.Schema and KafkaClient's consumers to serialize and
+// deserialize Event.Payload in Confluent wire format: a 0x00 magic byte, a 4-byte big-endian
+// schema id, then the schema-encoded bytes (Event.AdditionalFields is not schema-encoded, it
+// travels as a JSON message header). Resolved schemas are cached by
+// id/subject for CacheTTL to avoid a registry round trip on every publish/consume.
+type SchemaRegistry struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+
+	byID      map[int]cachedSchema
+	bySubject map[string]cachedSchema
+	cacheLock sync.RWMutex
+}
+
+// NewSchemaRegistry creates a SchemaRegistry client for the registry at registryURL
+func NewSchemaRegistry(registryURL string, config ...*SchemaRegistryConfig) *SchemaRegistry {
+	registry := &SchemaRegistry{
+		url:        strings.TrimRight(registryURL, "/"),
+		httpClient: http.DefaultClient,
+		cacheTTL:   defaultSchemaCacheTTL,
+		byID:       make(map[int]cachedSchema),
+		bySubject:  make(map[string]cachedSchema),
+	}
+
+	if len(config) > 0 && config[0] != nil {
+		cfg := config[0]
+
+		registry.username = cfg.Username
+		registry.password = cfg.Password
+
+		if cfg.HTTPClient != nil {
+			registry.httpClient = cfg.HTTPClient
+		}
+
+		if cfg.CacheTTL != 0 {
+			registry.cacheTTL = cfg.CacheTTL
+		}
+	}
+
+	return registry
+}
+
+// do executes an HTTP request against the registry, decoding the JSON response body into out
+// (when non-nil) and turning a non-2xx status into an error
+func (registry *SchemaRegistry) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reqBody *bytes.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, registry.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	if registry.username != "" {
+		req.SetBasicAuth(registry.username, registry.password)
+	}
+
+	resp, err := registry.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, respBody)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, out)
+}
+
+// GetByID fetches the schema registered under id, serving from cache when fresh
+func (registry *SchemaRegistry) GetByID(ctx context.Context, id int) (*Schema, error) {
+	if schema, ok := registry.cachedByID(id); ok {
+		return schema, nil
+	}
+
+	var result confluentSchema
+
+	if err := registry.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &result); err != nil {
+		return nil, fmt.Errorf("unable to fetch schema %d: %v", id, err)
+	}
+
+	result.ID = id
+
+	schema := result.toSchema()
+	registry.cache(schema)
+
+	return schema, nil
+}
+
+// GetLatest fetches the latest registered version of subject, serving from cache when fresh
+func (registry *SchemaRegistry) GetLatest(ctx context.Context, subject string) (*Schema, error) {
+	if schema, ok := registry.cachedBySubject(subject); ok {
+		return schema, nil
+	}
+
+	var result confluentSchema
+
+	path := fmt.Sprintf("/subjects/%s/versions/latest", url.PathEscape(subject))
+
+	if err := registry.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("unable to fetch latest schema for subject %s: %v", subject, err)
+	}
+
+	result.Subject = subject
+
+	schema := result.toSchema()
+	registry.cache(schema)
+
+	return schema, nil
+}
+
+// Register registers schemaText of schemaType under subject, returning the assigned id (or the
+// id of the identical existing version, since the registry is idempotent for unchanged schemas).
+// If compatibility is non-empty, it is applied to subject before registering.
+func (registry *SchemaRegistry) Register(ctx context.Context, subject string, schemaType SchemaType,
+	schemaText string, compatibility CompatibilityMode) (*Schema, error) {
+	if compatibility != "" {
+		if err := registry.SetCompatibility(ctx, subject, compatibility); err != nil {
+			return nil, fmt.Errorf("unable to set compatibility for subject %s: %v", subject, err)
+		}
+	}
+
+	body, err := json.Marshal(confluentSchema{Schema: schemaText, SchemaType: string(schemaType)})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		ID int `json:"id"`
+	}
+
+	path := fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject))
+
+	if err := registry.do(ctx, http.MethodPost, path, body, &result); err != nil {
+		return nil, fmt.Errorf("unable to register schema for subject %s: %v", subject, err)
+	}
+
+	schema := &Schema{ID: result.ID, Subject: subject, SchemaType: schemaType, Schema: schemaText}
+	registry.cache(schema)
+
+	return schema, nil
+}
+
+// SetCompatibility sets the compatibility mode enforced for new versions registered under subject
+func (registry *SchemaRegistry) SetCompatibility(ctx context.Context, subject string, mode CompatibilityMode) error {
+	body, err := json.Marshal(struct {
+		Compatibility string `json:"compatibility"`
+	}{Compatibility: string(mode)})
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/config/%s", url.PathEscape(subject))
+
+	return registry.do(ctx, http.MethodPut, path, body, nil)
+}
+
+func (registry *SchemaRegistry) cache(schema *Schema) {
+	registry.cacheLock.Lock()
+	defer registry.cacheLock.Unlock()
+
+	entry := cachedSchema{schema: schema, cachedAt: time.Now()}
+
+	registry.byID[schema.ID] = entry
+
+	if schema.Subject != "" {
+		registry.bySubject[schema.Subject] = entry
+	}
+}
+
+func (registry *SchemaRegistry) cachedByID(id int) (*Schema, bool) {
+	registry.cacheLock.RLock()
+	defer registry.cacheLock.RUnlock()
+
+	entry, ok := registry.byID[id]
+	if !ok || time.Since(entry.cachedAt) > registry.cacheTTL {
+		return nil, false
+	}
+
+	return entry.schema, true
+}
+
+func (registry *SchemaRegistry) cachedBySubject(subject string) (*Schema, bool) {
+	registry.cacheLock.RLock()
+	defer registry.cacheLock.RUnlock()
+
+	entry, ok := registry.bySubject[subject]
+	if !ok || time.Since(entry.cachedAt) > registry.cacheTTL {
+		return nil, false
+	}
+
+	return entry.schema, true
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SASLMechanismType selects the SASL mechanism used to authenticate with the broker
+type SASLMechanismType string
+
+const (
+	// SASLMechanismPlain authenticates with a plaintext username/password
+	SASLMechanismPlain SASLMechanismType = "PLAIN"
+
+	// SASLMechanismScramSHA256 authenticates using SCRAM-SHA-256
+	SASLMechanismScramSHA256 SASLMechanismType = "SCRAM-SHA-256"
+
+	// SASLMechanismScramSHA512 authenticates using SCRAM-SHA-512
+	SASLMechanismScramSHA512 SASLMechanismType = "SCRAM-SHA-512"
+
+	// SASLMechanismAWSMSKIAM authenticates using AWS MSK IAM signed tokens, see AWSIAMTokenProvider
+	SASLMechanismAWSMSKIAM SASLMechanismType = "AWS_MSK_IAM"
+)
+
+// AWSIAMTokenProvider supplies the signed auth token used by the AWS_MSK_IAM mechanism. Callers
+// wire their own implementation (e.g. backed by the AWS SDK session) via RegisterSASLMechanism.
+type AWSIAMTokenProvider interface {
+	Token() (string, error)
+}
+
+// TLSConfig configures transport security for the broker connection
+type TLSConfig struct {
+	// CACertPath is a path to a PEM encoded CA bundle, merged into CACertPool if both are set
+	CACertPath string
+
+	// CACertPool is used as-is when CACertPath is empty
+	CACertPool *x509.CertPool
+
+	// CertFile and KeyFile configure mutual TLS client authentication
+	CertFile string
+	KeyFile  string
+
+	InsecureSkipVerify bool
+	ServerName         string
+}
+
+// Security configures SASL authentication and TLS transport for BrokerConfig
+type Security struct {
+	SASLMechanism SASLMechanismType
+
+	// Username and Password are used by PLAIN and SCRAM mechanisms
+	Username string
+	Password string
+
+	// AWSIAMTokenProvider is required when SASLMechanism is SASLMechanismAWSMSKIAM
+	AWSIAMTokenProvider AWSIAMTokenProvider
+
+	TLS *TLSConfig
+}
+
+// saslMechanismFactory builds a sasl.Mechanism from a Security configuration
+type saslMechanismFactory func(security *Security) (sasl.Mechanism, error)
+
+// saslMechanismRegistry holds the built-in SASL mechanism factories keyed by SASLMechanismType.
+// RegisterSASLMechanism lets callers plug in custom sasl.Mechanism implementations without forking.
+var saslMechanismRegistry = map[SASLMechanismType]saslMechanismFactory{
+	SASLMechanismPlain: func(security *Security) (sasl.Mechanism, error) {
+		return plain.Mechanism{Username: security.Username, Password: security.Password}, nil
+	},
+	SASLMechanismScramSHA256: func(security *Security) (sasl.Mechanism, error) {
+		return scram.Mechanism(scram.SHA256, security.Username, security.Password)
+	},
+	SASLMechanismScramSHA512: func(security *Security) (sasl.Mechanism, error) {
+		return scram.Mechanism(scram.SHA512, security.Username, security.Password)
+	},
+}
+
+// RegisterSASLMechanism registers or overrides the factory used to build a sasl.Mechanism for
+// mechanism. Use this to wire up AWSMSKIAM or any other custom mechanism without forking the SDK.
+func RegisterSASLMechanism(mechanism SASLMechanismType, factory func(security *Security) (sasl.Mechanism, error)) {
+	saslMechanismRegistry[mechanism] = factory
+}
+
+// buildSASLMechanism looks up and builds the sasl.Mechanism configured by security
+func buildSASLMechanism(security *Security) (sasl.Mechanism, error) {
+	factory, ok := saslMechanismRegistry[security.SASLMechanism]
+	if !ok {
+		return nil, fmt.Errorf("unsupported SASL mechanism: %s", security.SASLMechanism)
+	}
+
+	return factory(security)
+}
+
+// buildTLSConfig builds a *tls.Config from a TLSConfig, loading the CA bundle and client
+// certificate from disk when paths are provided
+func buildTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		ServerName:         config.ServerName,
+		RootCAs:            config.CACertPool,
+	}
+
+	if config.CACertPath != "" {
+		caCert, err := ioutil.ReadFile(config.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA cert: %v", err)
+		}
+
+		pool := tlsConfig.RootCAs
+		if pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("unable to parse CA cert")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" && config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client cert/key: %v", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// applySecurity wires SASL and TLS settings from security into dialer
+func applySecurity(dialer *kafka.Dialer, security *Security) error {
+	if security.SASLMechanism != "" {
+		mechanism, err := buildSASLMechanism(security)
+		if err != nil {
+			return fmt.Errorf("unable to build SASL mechanism: %v", err)
+		}
+
+		dialer.SASLMechanism = mechanism
+	}
+
+	if security.TLS != nil {
+		tlsConfig, err := buildTLSConfig(security.TLS)
+		if err != nil {
+			return fmt.Errorf("unable to build TLS config: %v", err)
+		}
+
+		dialer.TLS = tlsConfig
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import "context"
+
+// SubscribeBuilder defines the required and optional fields to subscribe to a topic
+type SubscribeBuilder struct {
+	topic             string
+	groupID           string
+	eventName         string
+	callback          func(ctx context.Context, event *Event, err error)
+	callbackWithRetry func(ctx context.Context, event *Event) error
+	retryConfig       *RetryConfig
+	schemaTarget      func() interface{}
+	ctx               context.Context
+}
+
+// NewSubscribe creates a new SubscribeBuilder
+func NewSubscribe() *SubscribeBuilder {
+	return &SubscribeBuilder{
+		ctx: context.Background(),
+	}
+}
+
+// Topic sets the topic to subscribe to
+func (s *SubscribeBuilder) Topic(topic string) *SubscribeBuilder {
+	s.topic = topic
+	return s
+}
+
+// GroupID sets the consumer group id
+func (s *SubscribeBuilder) GroupID(groupID string) *SubscribeBuilder {
+	s.groupID = groupID
+	return s
+}
+
+// EventName sets the event name to filter on
+func (s *SubscribeBuilder) EventName(eventName string) *SubscribeBuilder {
+	s.eventName = eventName
+	return s
+}
+
+// Context sets the context used to run the subscription
+func (s *SubscribeBuilder) Context(ctx context.Context) *SubscribeBuilder {
+	s.ctx = ctx
+	return s
+}
+
+// Callback sets the function invoked for every received event
+func (s *SubscribeBuilder) Callback(callback func(ctx context.Context, event *Event, err error)) *SubscribeBuilder {
+	s.callback = callback
+	return s
+}
+
+// CallbackWithRetry sets the function invoked for every received event when registered through
+// KafkaClient.RegisterWithRetry. A returned error triggers the configured retry/dead-letter policy.
+func (s *SubscribeBuilder) CallbackWithRetry(callback func(ctx context.Context, event *Event) error) *SubscribeBuilder {
+	s.callbackWithRetry = callback
+	return s
+}
+
+// Retry sets the bounded retry and dead-letter policy used by KafkaClient.RegisterWithRetry
+func (s *SubscribeBuilder) Retry(retryConfig *RetryConfig) *SubscribeBuilder {
+	s.retryConfig = retryConfig
+	return s
+}
+
+// SchemaTarget registers a factory returning a fresh pointer to decode a FormatSchemaRegistry
+// message's payload into directly, attached to the callback's Event.SchemaValue. This is required
+// to consume a Protobuf subject, since its decoded value can't be represented as the
+// map[string]interface{} Event.Payload otherwise decodes into; e.g. SchemaTarget(func() interface{}
+// { return &mypb.MyMessage{} }).
+func (s *SubscribeBuilder) SchemaTarget(schemaTarget func() interface{}) *SubscribeBuilder {
+	s.schemaTarget = schemaTarget
+	return s
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2019 AccelByte Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventstream
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// constructTopic constructs the actual kafka topic name from the prefix and topic
+func constructTopic(prefix, topic string) string {
+	if prefix == "" {
+		return topic
+	}
+
+	return prefix + topic
+}
+
+// constructGroupID returns groupID as is, kept for symmetry with constructTopic
+func constructGroupID(groupID string) string {
+	return groupID
+}
+
+// generateID generates a random unique id used as the event id and message key
+func generateID() string {
+	return uuid.New().String()
+}
+
+// marshal marshals an event into its wire representation
+func marshal(event *Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// validatePublishEvent validates a publish builder before constructing the event
+func validatePublishEvent(publishBuilder *PublishBuilder, strictValidation bool) error {
+	if len(publishBuilder.topic) == 0 {
+		return errors.New("topic can't be empty")
+	}
+
+	if publishBuilder.eventName == "" {
+		return errors.New("event name can't be empty")
+	}
+
+	if strictValidation {
+		if publishBuilder.namespace == "" {
+			return errors.New("namespace can't be empty")
+		}
+	}
+
+	return nil
+}
+
+// validateSubscribeEvent validates a subscribe builder before registering it
+func validateSubscribeEvent(subscribeBuilder *SubscribeBuilder) error {
+	if subscribeBuilder.topic == "" {
+		return errors.New("topic can't be empty")
+	}
+
+	if subscribeBuilder.callback == nil && subscribeBuilder.callbackWithRetry == nil {
+		return errors.New("callback can't be nil")
+	}
+
+	return nil
+}